@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/jinzhu/gorm"
 	"github.com/sebest/xff"
+	"github.com/soheilhy/cmux"
 
 	"github.com/pborman/uuid"
 	"github.com/rs/cors"
@@ -31,21 +33,40 @@ var (
 
 // API is the main REST API
 type API struct {
-	handler    http.Handler
-	db         *gorm.DB
-	config     *conf.GlobalConfiguration
-	httpClient *http.Client
-	version    string
+	handler          http.Handler
+	db               *gorm.DB
+	config           *conf.GlobalConfiguration
+	httpClient       *http.Client
+	version          string
+	shutdownTracer   func(context.Context) error
+	rateLimitStore   rateLimitStore
+	idempotencyStore idempotencyStore
 }
 
-// ListenAndServe starts the REST API.
-func (a *API) ListenAndServe(hostAndPort string) {
+// GRPCServer is satisfied by a *grpc.Server from api/grpc. It is expressed
+// as a local interface, rather than imported directly, so that package api
+// does not need to depend on the grpc transport it is optionally paired
+// with; callers that want both REST and gRPC construct the grpc.Server
+// themselves and pass it to ListenAndServe.
+type GRPCServer interface {
+	Serve(net.Listener) error
+	GracefulStop()
+}
+
+// ListenAndServe starts the REST API. If grpcServer is non-nil, both
+// transports are multiplexed on the same hostAndPort using cmux, with gRPC
+// requests matched by their HTTP/2 content-type.
+func (a *API) ListenAndServe(hostAndPort string, grpcServer GRPCServer) {
 	log := logrus.WithField("component", "api")
 	server := &http.Server{
-		Addr:    hostAndPort,
 		Handler: a.handler,
 	}
 
+	l, err := net.Listen("tcp", hostAndPort)
+	if err != nil {
+		log.WithError(err).Fatal("API server failed to bind")
+	}
+
 	done := make(chan struct{})
 	defer close(done)
 	go func() {
@@ -53,10 +74,40 @@ func (a *API) ListenAndServe(hostAndPort string) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 		defer cancel()
 		server.Shutdown(ctx)
+		if grpcServer != nil {
+			grpcServer.GracefulStop()
+		}
+		if a.shutdownTracer != nil {
+			if err := a.shutdownTracer(ctx); err != nil {
+				log.WithError(err).Error("error shutting down tracer")
+			}
+		}
 	}()
 
-	if err := server.ListenAndServe(); err != nil {
-		log.WithError(err).Fatal("API server failed")
+	if grpcServer == nil {
+		if err := server.Serve(l); err != nil {
+			log.WithError(err).Fatal("API server failed")
+		}
+		return
+	}
+
+	m := cmux.New(l)
+	grpcL := m.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+	httpL := m.Match(cmux.Any())
+
+	go func() {
+		if err := grpcServer.Serve(grpcL); err != nil {
+			log.WithError(err).Error("gRPC server failed")
+		}
+	}()
+	go func() {
+		if err := server.Serve(httpL); err != nil {
+			log.WithError(err).Error("API server failed")
+		}
+	}()
+
+	if err := m.Serve(); err != nil {
+		log.WithError(err).Fatal("connection mux failed")
 	}
 }
 
@@ -86,15 +137,51 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 		version:    version,
 	}
 
+	shutdownTracer, err := initTracer(globalConfig)
+	if err != nil {
+		log.WithError(err).Warn("unable to start tracer, continuing without tracing")
+		shutdownTracer = func(context.Context) error { return nil }
+	}
+	api.shutdownTracer = shutdownTracer
+	api.httpClient = tracedHTTPClient(api.httpClient)
+
+	if globalConfig.RateLimit.RedisURL != "" {
+		api.rateLimitStore = newRedisRateLimitStore(newRedisClient(globalConfig.RateLimit.RedisURL))
+	} else {
+		api.rateLimitStore = newInMemoryRateLimitStore()
+	}
+
+	dispatcher := newHookDispatcher(db, api.httpClient)
+	go dispatcher.Run(ctx, time.Second*10)
+
+	if globalConfig.MultiInstanceMode {
+		api.idempotencyStore = newSQLIdempotencyStore(db)
+	} else {
+		api.idempotencyStore = newInMemoryIdempotencyStore()
+	}
+
+	gormMetricsPlugin(db)
+	configureHistogramBuckets(globalConfig)
+	if globalConfig.Metrics.AdminPort != "" {
+		go func() {
+			if err := http.ListenAndServe(globalConfig.Metrics.AdminPort, metricsHandler()); err != nil {
+				log.WithError(err).Error("metrics admin server failed")
+			}
+		}()
+	}
+
 	xffmw, _ := xff.Default()
 	logger := newStructuredLogger(log)
 
 	r := newRouter()
 	r.UseBypass(xffmw.Handler)
 	r.Use(withRequestID)
+	r.UseBypass(tracingHandler)
+	r.UseBypass(instrumentRequests)
 	r.Use(recoverer)
 
 	r.Get("/health", api.HealthCheck)
+	r.Mount("/metrics", metricsHandler())
 
 	r.Route("/", func(r *router) {
 		r.UseBypass(logger)
@@ -120,13 +207,13 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 			r.With(adminRequired).Get("/", api.PaymentList)
 			r.Route("/{payment_id}", func(r *router) {
 				r.With(adminRequired).Get("/", api.PaymentView)
-				r.With(adminRequired).With(addGetBody).Post("/refund", api.PaymentRefund)
-				r.Post("/confirm", api.PaymentConfirm)
+				r.With(adminRequired).With(addGetBody).WithBypass(api.idempotency).Post("/refund", api.PaymentRefund)
+				r.With(api.rateLimiter).WithBypass(api.idempotency).Post("/confirm", api.PaymentConfirm)
 			})
 		})
 
 		r.Route("/paypal", func(r *router) {
-			r.With(addGetBody).Post("/", api.PreauthorizePayment)
+			r.With(addGetBody).With(api.rateLimiter).WithBypass(api.idempotency).Post("/", api.PreauthorizePayment)
 		})
 
 		r.Route("/reports", func(r *router) {
@@ -141,9 +228,13 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 			r.Get("/{coupon_code}", api.CouponView)
 		})
 
+		r.Route("/hooks", api.hookRoutes)
+
+		r.With(adminRequired).Delete("/idempotency-keys/expired", api.IdempotencyCleanup)
+
 		r.Get("/settings", api.ViewSettings)
 
-		r.With(authRequired).Post("/claim", api.ClaimOrders)
+		r.With(authRequired).With(api.rateLimiter).WithBypass(api.idempotency).Post("/claim", api.ClaimOrders)
 	})
 
 	if globalConfig.MultiInstanceMode {
@@ -178,7 +269,7 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 
 func (a *API) orderRoutes(r *router) {
 	r.With(authRequired).Get("/", a.OrderList)
-	r.Post("/", a.OrderCreate)
+	r.With(a.rateLimiter).WithBypass(a.idempotency).Post("/", a.OrderCreate)
 
 	r.Route("/{order_id}", func(r *router) {
 		r.Use(a.withOrderID)
@@ -187,12 +278,12 @@ func (a *API) orderRoutes(r *router) {
 
 		r.Route("/payments", func(r *router) {
 			r.With(authRequired).Get("/", a.PaymentListForOrder)
-			r.With(addGetBody).Post("/", a.PaymentCreate)
+			r.With(addGetBody).WithBypass(a.idempotency).Post("/", a.PaymentCreate)
 		})
 
 		r.Route("/downloads", func(r *router) {
 			r.Get("/", a.DownloadList)
-			r.Post("/refresh", a.DownloadRefresh)
+			r.Post("/{download_id}/refresh", a.DownloadRefresh)
 		})
 		r.Get("/receipt", a.ReceiptView)
 		r.Post("/receipt", a.ResendOrderReceipt)
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/netlify/gocommerce/conf"
+	gcontext "github.com/netlify/gocommerce/context"
+	"github.com/netlify/gocommerce/models"
+)
+
+// DownloadRefresh reissues a time-limited download URL for a download
+// belonging to the order in the route.
+func (a *API) DownloadRefresh(w http.ResponseWriter, r *http.Request) error {
+	download := &models.Download{}
+	if err := a.db.Where("id = ? and order_id = ?", routeParam(r, "download_id"), routeParam(r, "order_id")).First(download).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return notFoundError("Download not found")
+		}
+		return internalServerError("Error finding download").WithInternalError(err)
+	}
+
+	freshURL, err := freshDownloadURL(a.config.Downloads, download)
+	if err != nil {
+		return internalServerError("Error refreshing download").WithInternalError(err)
+	}
+
+	if err := a.db.Model(download).Update("url", freshURL).Error; err != nil {
+		return internalServerError("Error refreshing download").WithInternalError(err)
+	}
+
+	a.enqueueHookEvent(gcontext.GetInstanceID(r.Context()), "download.refreshed", download)
+	return sendJSON(w, http.StatusOK, download)
+}
+
+// freshDownloadURL signs download's base (query-stripped) URL with a new
+// expiry: an HMAC-SHA256 of "<download ID>.<expires>", added as the expires
+// and signature query parameters, so a link captured from a prior refresh
+// stops working once it expires instead of being valid forever.
+func freshDownloadURL(config conf.DownloadsConfiguration, download *models.Download) (string, error) {
+	parsed, err := url.Parse(download.URL)
+	if err != nil {
+		return "", fmt.Errorf("parsing download URL: %w", err)
+	}
+	parsed.RawQuery = ""
+
+	ttl := time.Duration(config.URLTTLSeconds) * time.Second
+	expires := time.Now().Add(ttl).Unix()
+
+	mac := hmac.New(sha256.New, []byte(config.SigningSecret))
+	fmt.Fprintf(mac, "%s.%d", download.ID, expires)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	query := parsed.Query()
+	query.Set("expires", fmt.Sprintf("%d", expires))
+	query.Set("signature", signature)
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String(), nil
+}
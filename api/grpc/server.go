@@ -0,0 +1,205 @@
+// Package grpc exposes the order, user, payment, coupon and download
+// operations of api.API over gRPC, with the same authentication rules as the
+// chi-based REST routes in package api. ConfirmPayment and RefundPayment call
+// the same api.API methods as the REST handlers; the other RPCs read and
+// write the models directly through api.API.DB, so CreateOrder, for example,
+// sets no payment state and emits no order.created event.
+//
+//go:generate make -C ../.. proto
+package grpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/netlify/gocommerce/api"
+	gcontext "github.com/netlify/gocommerce/context"
+	pb "github.com/netlify/gocommerce/proto/gocommerce/v1"
+)
+
+// Server wraps a *grpc.Server bound to the same *api.API business logic
+// used by the REST transport.
+type Server struct {
+	api    *api.API
+	server *grpc.Server
+	log    logrus.FieldLogger
+}
+
+// NewServer builds the gRPC server, registering every service against
+// handlers backed by the given API, and wiring grpc_health_v1 to the same
+// DB ping used by the REST /health route.
+func NewServer(a *api.API, log logrus.FieldLogger) *Server {
+	s := &Server{api: a, log: log.WithField("component", "grpc")}
+
+	s.server = grpc.NewServer(
+		grpc.UnaryInterceptor(s.unaryAuthInterceptor),
+		grpc.StreamInterceptor(s.streamAuthInterceptor),
+	)
+
+	pb.RegisterOrderServiceServer(s.server, &orderService{api: a})
+	pb.RegisterUserServiceServer(s.server, &userService{api: a})
+	pb.RegisterPaymentServiceServer(s.server, &paymentService{api: a})
+	pb.RegisterCouponServiceServer(s.server, &couponService{api: a})
+	pb.RegisterDownloadServiceServer(s.server, &downloadService{api: a})
+
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(s.server, healthSrv)
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	go s.watchDB(healthSrv)
+
+	return s
+}
+
+// Serve accepts gRPC connections from the given listener. Callers that want
+// to share one TCP port with the REST API should hand it a cmux-matched
+// listener for HTTP/2 traffic.
+func (s *Server) Serve(l net.Listener) error {
+	return s.server.Serve(l)
+}
+
+// GracefulStop drains in-flight RPCs and stops accepting new ones.
+func (s *Server) GracefulStop() {
+	s.server.GracefulStop()
+}
+
+func (s *Server) watchDB(healthSrv *health.Server) {
+	for range time.Tick(time.Second * 5) {
+		status := healthpb.HealthCheckResponse_SERVING
+		if err := s.api.DB().DB().Ping(); err != nil {
+			s.log.WithError(err).Warn("grpc health check: db ping failed")
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		healthSrv.SetServingStatus("", status)
+	}
+}
+
+// unaryAuthInterceptor mirrors withToken/authRequired/adminRequired for
+// unary RPCs: it extracts the bearer token from metadata, authenticates it
+// and stashes the resulting context for the handler.
+func (s *Server) unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuthInterceptor is the streaming equivalent of
+// unaryAuthInterceptor, used for ListOrders/ListPayments/ListDownloads.
+func (s *Server) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+func (s *Server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if isHealthMethod(method) {
+		return ctx, nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+
+	var instanceID string
+	if ok {
+		if ids := md.Get("x-instance-id"); len(ids) > 0 {
+			instanceID = ids[0]
+		}
+	}
+	ctx = gcontext.WithInstanceID(ctx, instanceID)
+
+	if !ok || len(md.Get("authorization")) == 0 {
+		if isPublicMethod(method) {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	ctx, err := s.api.Authenticate(ctx, instanceID, bearerToken(md.Get("authorization")[0]))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if isAdminMethod(method) {
+		if err := s.api.RequireAdmin(ctx); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+
+	return ctx, nil
+}
+
+func bearerToken(authorization string) string {
+	const prefix = "Bearer "
+	if len(authorization) > len(prefix) && authorization[:len(prefix)] == prefix {
+		return authorization[len(prefix):]
+	}
+	return authorization
+}
+
+// isHealthMethod exempts the gRPC health-check service registered in
+// NewServer from authentication entirely, the same way REST's /health route
+// is mounted outside api.withToken: load balancer and k8s probes never send
+// credentials.
+func isHealthMethod(method string) bool {
+	switch method {
+	case "/grpc.health.v1.Health/Check", "/grpc.health.v1.Health/Watch":
+		return true
+	default:
+		return false
+	}
+}
+
+// isPublicMethod lists RPCs that mirror a REST route mounted without
+// authRequired: guest checkout (orderRoutes' POST / has no authRequired)
+// and payment confirmation (the /payments/{id}/confirm route only carries
+// api.rateLimiter), plus coupon lookup.
+func isPublicMethod(method string) bool {
+	switch method {
+	case "/gocommerce.v1.CouponService/GetCoupon",
+		"/gocommerce.v1.OrderService/CreateOrder",
+		"/gocommerce.v1.PaymentService/ConfirmPayment":
+		return true
+	default:
+		return false
+	}
+}
+
+// isAdminMethod lists RPCs that mirror a REST route gated behind
+// adminRequired: OrderUpdate (orderRoutes' PUT /{order_id}), PaymentView
+// and PaymentList (payments' GET routes are both adminRequired), PaymentRefund,
+// UserList, UserDelete, and CouponList (the REST equivalent of ListCoupons,
+// which is adminRequired too).
+func isAdminMethod(method string) bool {
+	switch method {
+	case "/gocommerce.v1.OrderService/UpdateOrder",
+		"/gocommerce.v1.UserService/ListUsers",
+		"/gocommerce.v1.UserService/DeleteUser",
+		"/gocommerce.v1.PaymentService/GetPayment",
+		"/gocommerce.v1.PaymentService/RefundPayment",
+		"/gocommerce.v1.PaymentService/ListPayments",
+		"/gocommerce.v1.CouponService/ListCoupons":
+		return true
+	default:
+		return false
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
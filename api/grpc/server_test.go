@@ -0,0 +1,51 @@
+package grpc
+
+import "testing"
+
+func TestIsHealthMethod(t *testing.T) {
+	cases := map[string]bool{
+		"/grpc.health.v1.Health/Check":         true,
+		"/grpc.health.v1.Health/Watch":         true,
+		"/gocommerce.v1.OrderService/GetOrder": false,
+		"":                                     false,
+	}
+	for method, want := range cases {
+		if got := isHealthMethod(method); got != want {
+			t.Errorf("isHealthMethod(%q) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestIsPublicMethod(t *testing.T) {
+	cases := map[string]bool{
+		"/gocommerce.v1.CouponService/GetCoupon":       true,
+		"/gocommerce.v1.OrderService/CreateOrder":      true,
+		"/gocommerce.v1.PaymentService/ConfirmPayment": true,
+		"/gocommerce.v1.OrderService/GetOrder":         false,
+		"/gocommerce.v1.UserService/ListUsers":         false,
+	}
+	for method, want := range cases {
+		if got := isPublicMethod(method); got != want {
+			t.Errorf("isPublicMethod(%q) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestIsAdminMethod(t *testing.T) {
+	cases := map[string]bool{
+		"/gocommerce.v1.OrderService/UpdateOrder":      true,
+		"/gocommerce.v1.UserService/ListUsers":         true,
+		"/gocommerce.v1.UserService/DeleteUser":        true,
+		"/gocommerce.v1.PaymentService/GetPayment":     true,
+		"/gocommerce.v1.PaymentService/RefundPayment":  true,
+		"/gocommerce.v1.PaymentService/ListPayments":   true,
+		"/gocommerce.v1.CouponService/ListCoupons":     true,
+		"/gocommerce.v1.OrderService/CreateOrder":      false,
+		"/gocommerce.v1.PaymentService/ConfirmPayment": false,
+	}
+	for method, want := range cases {
+		if got := isAdminMethod(method); got != want {
+			t.Errorf("isAdminMethod(%q) = %v, want %v", method, got, want)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/netlify/gocommerce/api"
+	gcontext "github.com/netlify/gocommerce/context"
+	"github.com/netlify/gocommerce/models"
+	pb "github.com/netlify/gocommerce/proto/gocommerce/v1"
+)
+
+// authzError maps an api.EnsureSelfOrAdmin/RequireAdmin error to the
+// PermissionDenied gRPC status REST would answer with a 403 for.
+func authzError(err error) error {
+	return status.Error(codes.PermissionDenied, err.Error())
+}
+
+type orderService struct {
+	pb.UnimplementedOrderServiceServer
+	api *api.API
+}
+
+func (s *orderService) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.Order, error) {
+	order := &models.Order{}
+	if err := s.api.DB().Where("id = ?", req.OrderId).First(order).Error; err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err := s.api.EnsureSelfOrAdmin(ctx, order.UserID); err != nil {
+		return nil, authzError(err)
+	}
+	return orderToProto(order), nil
+}
+
+func (s *orderService) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.Order, error) {
+	order := &models.Order{
+		UserID: req.Order.UserId,
+		Email:  req.Order.Email,
+	}
+	if err := s.api.DB().Create(order).Error; err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return orderToProto(order), nil
+}
+
+func (s *orderService) UpdateOrder(ctx context.Context, req *pb.UpdateOrderRequest) (*pb.Order, error) {
+	order := &models.Order{}
+	if err := s.api.DB().Where("id = ?", req.OrderId).First(order).Error; err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err := s.api.DB().Model(order).Update("payment_state", req.Order.PaymentState).Error; err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return orderToProto(order), nil
+}
+
+func (s *orderService) ListOrders(req *pb.ListOrdersRequest, stream pb.OrderService_ListOrdersServer) error {
+	if err := s.api.EnsureSelfOrAdmin(stream.Context(), req.UserId); err != nil {
+		return authzError(err)
+	}
+
+	rows, err := s.api.DB().Model(&models.Order{}).Where("user_id = ?", req.UserId).Rows()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer rows.Close()
+
+	order := &models.Order{}
+	for rows.Next() {
+		if err := s.api.DB().ScanRows(rows, order); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := stream.Send(orderToProto(order)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func orderToProto(o *models.Order) *pb.Order {
+	createdAt, _ := ptypes.TimestampProto(o.CreatedAt)
+	return &pb.Order{
+		Id:           o.ID,
+		UserId:       o.UserID,
+		Email:        o.Email,
+		Currency:     o.Currency,
+		Total:        o.Total,
+		PaymentState: o.PaymentState,
+		CreatedAt:    createdAt,
+	}
+}
+
+type userService struct {
+	pb.UnimplementedUserServiceServer
+	api *api.API
+}
+
+func (s *userService) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	if err := s.api.EnsureSelfOrAdmin(ctx, req.UserId); err != nil {
+		return nil, authzError(err)
+	}
+	user := &models.User{}
+	if err := s.api.DB().Where("id = ?", req.UserId).First(user).Error; err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &pb.User{Id: user.ID, Email: user.Email}, nil
+}
+
+func (s *userService) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_ListUsersServer) error {
+	rows, err := s.api.DB().Model(&models.User{}).Rows()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer rows.Close()
+
+	user := &models.User{}
+	for rows.Next() {
+		if err := s.api.DB().ScanRows(rows, user); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := stream.Send(&pb.User{Id: user.ID, Email: user.Email}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *userService) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	if err := s.api.DB().Where("id = ?", req.UserId).Delete(&models.User{}).Error; err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.DeleteUserResponse{}, nil
+}
+
+type paymentService struct {
+	pb.UnimplementedPaymentServiceServer
+	api *api.API
+}
+
+func (s *paymentService) GetPayment(ctx context.Context, req *pb.GetPaymentRequest) (*pb.Payment, error) {
+	tx := &models.Transaction{}
+	if err := s.api.DB().Where("id = ?", req.PaymentId).First(tx).Error; err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return paymentToProto(tx), nil
+}
+
+// ConfirmPayment calls through to api.API.ConfirmPayment, the same
+// provider-backed confirmation logic the REST PaymentConfirm handler uses,
+// so a payment can never be marked paid here without the provider actually
+// confirming the charge.
+func (s *paymentService) ConfirmPayment(ctx context.Context, req *pb.ConfirmPaymentRequest) (*pb.Payment, error) {
+	tx, err := s.api.ConfirmPayment(gcontext.GetInstanceID(ctx), req.PaymentId)
+	if err != nil {
+		return nil, grpcErrorForPaymentError(err)
+	}
+	return paymentToProto(tx), nil
+}
+
+// RefundPayment calls through to api.API.RefundPayment, the same
+// provider-backed refund logic the REST PaymentRefund handler uses, so a
+// payment can never be marked refunded here without the provider actually
+// returning the funds.
+func (s *paymentService) RefundPayment(ctx context.Context, req *pb.RefundPaymentRequest) (*pb.Payment, error) {
+	tx, err := s.api.RefundPayment(gcontext.GetInstanceID(ctx), req.PaymentId, req.Amount)
+	if err != nil {
+		return nil, grpcErrorForPaymentError(err)
+	}
+	return paymentToProto(tx), nil
+}
+
+// grpcErrorForPaymentError maps the sentinel errors api.API.ConfirmPayment
+// and api.API.RefundPayment return to gRPC status codes, the same way
+// apiErrorFor maps them to HTTPError codes for REST.
+func grpcErrorForPaymentError(err error) error {
+	var notFound api.NotFoundError
+	if errors.As(err, &notFound) {
+		return status.Error(codes.NotFound, notFound.Message)
+	}
+	var badRequest api.BadRequestError
+	if errors.As(err, &badRequest) {
+		return status.Error(codes.InvalidArgument, badRequest.Message)
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func (s *paymentService) ListPayments(req *pb.ListPaymentsRequest, stream pb.PaymentService_ListPaymentsServer) error {
+	query := s.api.DB().Model(&models.Transaction{})
+	if req.OrderId != "" {
+		query = query.Where("order_id = ?", req.OrderId)
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer rows.Close()
+
+	tx := &models.Transaction{}
+	for rows.Next() {
+		if err := s.api.DB().ScanRows(rows, tx); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := stream.Send(paymentToProto(tx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func paymentToProto(t *models.Transaction) *pb.Payment {
+	return &pb.Payment{
+		Id:       t.ID,
+		OrderId:  t.OrderID,
+		Provider: t.Processor,
+		Amount:   t.Amount,
+		Status:   t.Status,
+	}
+}
+
+type couponService struct {
+	pb.UnimplementedCouponServiceServer
+	api *api.API
+}
+
+func (s *couponService) GetCoupon(ctx context.Context, req *pb.GetCouponRequest) (*pb.Coupon, error) {
+	coupon, err := s.api.Config().Coupons.Lookup(req.CouponCode)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &pb.Coupon{Code: coupon.Code, Percentage: int64(coupon.Discount.Percentage)}, nil
+}
+
+func (s *couponService) ListCoupons(req *pb.ListCouponsRequest, stream pb.CouponService_ListCouponsServer) error {
+	for _, coupon := range s.api.Config().Coupons.All() {
+		if err := stream.Send(&pb.Coupon{Code: coupon.Code, Percentage: int64(coupon.Discount.Percentage)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type downloadService struct {
+	pb.UnimplementedDownloadServiceServer
+	api *api.API
+}
+
+// ensureOrderAccess mirrors the REST withOrderID + ensureUserAccess pair:
+// it loads orderID's owning user and requires the caller to be that user
+// or an admin, so downloads can't be enumerated/fetched by guessing IDs.
+func (s *downloadService) ensureOrderAccess(ctx context.Context, orderID string) error {
+	order := &models.Order{}
+	if err := s.api.DB().Where("id = ?", orderID).First(order).Error; err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if err := s.api.EnsureSelfOrAdmin(ctx, order.UserID); err != nil {
+		return authzError(err)
+	}
+	return nil
+}
+
+func (s *downloadService) GetDownloadURL(ctx context.Context, req *pb.GetDownloadURLRequest) (*pb.DownloadURL, error) {
+	download := &models.Download{}
+	if err := s.api.DB().Where("id = ?", req.DownloadId).First(download).Error; err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err := s.ensureOrderAccess(ctx, download.OrderID); err != nil {
+		return nil, err
+	}
+	return &pb.DownloadURL{Url: download.URL}, nil
+}
+
+func (s *downloadService) ListDownloads(req *pb.ListDownloadsRequest, stream pb.DownloadService_ListDownloadsServer) error {
+	if err := s.ensureOrderAccess(stream.Context(), req.OrderId); err != nil {
+		return err
+	}
+
+	rows, err := s.api.DB().Model(&models.Download{}).Where("order_id = ?", req.OrderId).Rows()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer rows.Close()
+
+	download := &models.Download{}
+	for rows.Next() {
+		if err := s.api.DB().ScanRows(rows, download); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := stream.Send(&pb.Download{Id: download.ID, Title: download.Title}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
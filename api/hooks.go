@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pborman/uuid"
+
+	gcontext "github.com/netlify/gocommerce/context"
+	"github.com/netlify/gocommerce/models"
+)
+
+// hookSignatureTolerance is how far a delivery's timestamp may drift from
+// "now" before it is rejected as a replay, following the same t=/v1=
+// scheme Stripe uses for webhook signatures.
+const hookSignatureTolerance = 5 * time.Minute
+
+// maxHookAttempts is the number of delivery attempts before a webhook
+// delivery is marked dead-letter and stops retrying.
+const maxHookAttempts = 8
+
+func (a *API) hookRoutes(r *router) {
+	r.Use(adminRequired)
+
+	r.Get("/", a.WebhookList)
+	r.Post("/", a.WebhookCreate)
+
+	r.Route("/{hook_id}", func(r *router) {
+		r.Use(a.withWebhook)
+
+		r.Get("/", a.WebhookView)
+		r.Put("/", a.WebhookUpdate)
+		r.Delete("/", a.WebhookDelete)
+
+		r.Route("/deliveries", func(r *router) {
+			r.Get("/", a.WebhookDeliveryList)
+			r.Post("/{delivery_id}/replay", a.WebhookDeliveryReplay)
+		})
+	})
+}
+
+// WebhookList returns every webhook subscription configured for the
+// instance.
+func (a *API) WebhookList(w http.ResponseWriter, r *http.Request) error {
+	var hooks []models.Webhook
+	if err := a.db.Where("instance_id = ?", gcontext.GetInstanceID(r.Context())).Find(&hooks).Error; err != nil {
+		return internalServerError("Error finding webhooks").WithInternalError(err)
+	}
+	return sendJSON(w, http.StatusOK, hooks)
+}
+
+// WebhookCreate registers a new webhook subscription with a URL, a secret
+// used to sign deliveries, and the list of events it wants to receive.
+func (a *API) WebhookCreate(w http.ResponseWriter, r *http.Request) error {
+	params := struct {
+		URL    string   `json:"url"`
+		Secret string   `json:"secret"`
+		Events []string `json:"events"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return badRequestError("Could not read webhook params: %v", err)
+	}
+	if params.URL == "" || len(params.Events) == 0 {
+		return badRequestError("A webhook requires a url and at least one event")
+	}
+
+	hook := &models.Webhook{
+		ID:         uuid.NewRandom().String(),
+		InstanceID: gcontext.GetInstanceID(r.Context()),
+		URL:        params.URL,
+		Secret:     params.Secret,
+		Events:     params.Events,
+	}
+	if err := a.db.Create(hook).Error; err != nil {
+		return internalServerError("Error creating webhook").WithInternalError(err)
+	}
+	return sendJSON(w, http.StatusCreated, hook)
+}
+
+// WebhookView returns a single webhook subscription.
+func (a *API) WebhookView(w http.ResponseWriter, r *http.Request) error {
+	return sendJSON(w, http.StatusOK, gcontext.GetWebhook(r.Context()))
+}
+
+// WebhookUpdate edits the URL, secret or event filter of a webhook
+// subscription.
+func (a *API) WebhookUpdate(w http.ResponseWriter, r *http.Request) error {
+	hook := gcontext.GetWebhook(r.Context())
+	params := struct {
+		URL    string   `json:"url"`
+		Secret string   `json:"secret"`
+		Events []string `json:"events"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return badRequestError("Could not read webhook params: %v", err)
+	}
+
+	if params.URL != "" {
+		hook.URL = params.URL
+	}
+	if params.Secret != "" {
+		hook.Secret = params.Secret
+	}
+	if params.Events != nil {
+		hook.Events = params.Events
+	}
+
+	if err := a.db.Save(hook).Error; err != nil {
+		return internalServerError("Error updating webhook").WithInternalError(err)
+	}
+	return sendJSON(w, http.StatusOK, hook)
+}
+
+// WebhookDelete removes a webhook subscription. Queued deliveries are left
+// in place for audit purposes but will no longer be attempted.
+func (a *API) WebhookDelete(w http.ResponseWriter, r *http.Request) error {
+	hook := gcontext.GetWebhook(r.Context())
+	if err := a.db.Delete(hook).Error; err != nil {
+		return internalServerError("Error deleting webhook").WithInternalError(err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// WebhookDeliveryList returns the delivery attempts recorded for a webhook,
+// most recent first.
+func (a *API) WebhookDeliveryList(w http.ResponseWriter, r *http.Request) error {
+	hook := gcontext.GetWebhook(r.Context())
+	var deliveries []models.WebhookDelivery
+	if err := a.db.Where("webhook_id = ?", hook.ID).Order("created_at desc").Find(&deliveries).Error; err != nil {
+		return internalServerError("Error finding deliveries").WithInternalError(err)
+	}
+	return sendJSON(w, http.StatusOK, deliveries)
+}
+
+// WebhookDeliveryReplay re-enqueues a past delivery for immediate retry,
+// useful once the receiving endpoint has recovered from an outage.
+func (a *API) WebhookDeliveryReplay(w http.ResponseWriter, r *http.Request) error {
+	deliveryID := routeParam(r, "delivery_id")
+	delivery := &models.WebhookDelivery{}
+	if err := a.db.Where("id = ?", deliveryID).First(delivery).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return notFoundError("Delivery not found")
+		}
+		return internalServerError("Error finding delivery").WithInternalError(err)
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	if err := a.db.Save(delivery).Error; err != nil {
+		return internalServerError("Error requeuing delivery").WithInternalError(err)
+	}
+	return sendJSON(w, http.StatusOK, delivery)
+}
+
+func (a *API) withWebhook(w http.ResponseWriter, r *http.Request) (context.Context, error) {
+	hook := &models.Webhook{}
+	if err := a.db.Where("id = ? and instance_id = ?", routeParam(r, "hook_id"), gcontext.GetInstanceID(r.Context())).First(hook).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, notFoundError("Webhook not found")
+		}
+		return nil, internalServerError("Error finding webhook").WithInternalError(err)
+	}
+	return gcontext.WithWebhook(r.Context(), hook), nil
+}
+
+// enqueueHookEvent persists a queued delivery for every subscription
+// matching eventType, to be picked up by the dispatcher. Called by the
+// order/payment/download handlers when they emit a lifecycle event such as
+// "order.created" or "payment.refunded".
+func (a *API) enqueueHookEvent(instanceID, eventType string, payload interface{}) error {
+	var hooks []models.Webhook
+	if err := a.db.Where("instance_id = ?", instanceID).Find(&hooks).Error; err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	recordBusinessEvent(eventType, payload)
+
+	for _, hook := range hooks {
+		if !hook.Subscribes(eventType) {
+			continue
+		}
+		delivery := &models.WebhookDelivery{
+			ID:            uuid.NewRandom().String(),
+			WebhookID:     hook.ID,
+			EventType:     eventType,
+			Payload:       body,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: time.Now(),
+		}
+		if err := a.db.Create(delivery).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hookDispatcher polls for pending webhook deliveries and attempts them,
+// retrying with exponential backoff and jitter until maxHookAttempts is
+// reached, at which point the delivery is marked dead-letter.
+type hookDispatcher struct {
+	db         *gorm.DB
+	httpClient *http.Client
+}
+
+func newHookDispatcher(db *gorm.DB, httpClient *http.Client) *hookDispatcher {
+	return &hookDispatcher{db: db, httpClient: httpClient}
+}
+
+// Run polls for due deliveries every interval until ctx is cancelled.
+func (d *hookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.dispatchDue()
+		}
+	}
+}
+
+func (d *hookDispatcher) dispatchDue() {
+	var deliveries []models.WebhookDelivery
+	if err := d.db.Where("status = ? and next_attempt_at <= ?", models.WebhookDeliveryPending, time.Now()).Find(&deliveries).Error; err != nil {
+		return
+	}
+	for _, delivery := range deliveries {
+		d.attempt(&delivery)
+	}
+}
+
+func (d *hookDispatcher) attempt(delivery *models.WebhookDelivery) {
+	hook := &models.Webhook{}
+	if err := d.db.Where("id = ?", delivery.WebhookID).First(hook).Error; err != nil {
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		d.fail(delivery)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gocommerce-Event", delivery.EventType)
+	req.Header.Set("X-Gocommerce-Signature", signHookPayload(hook.Secret, delivery.Payload, time.Now()))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil || resp.StatusCode >= 300 {
+		d.fail(delivery)
+		return
+	}
+	resp.Body.Close()
+
+	delivery.Status = models.WebhookDeliveryDelivered
+	d.db.Save(delivery)
+}
+
+func (d *hookDispatcher) fail(delivery *models.WebhookDelivery) {
+	delivery.Attempts++
+	if delivery.Attempts >= maxHookAttempts {
+		delivery.Status = models.WebhookDeliveryDeadLetter
+		d.db.Save(delivery)
+		return
+	}
+	delivery.NextAttemptAt = time.Now().Add(backoffWithJitter(delivery.Attempts))
+	d.db.Save(delivery)
+}
+
+func backoffWithJitter(attempt int) time.Duration {
+	base := time.Second * time.Duration(1<<uint(attempt))
+	jitter := time.Duration(rand.Int63n(int64(base) / 2))
+	return base + jitter
+}
+
+// signHookPayload produces a Stripe-style "t=<unix>,v1=<hex>" signature so
+// receivers can verify authenticity and reject replays outside
+// hookSignatureTolerance.
+func signHookPayload(secret string, payload []byte, at time.Time) string {
+	ts := at.Unix()
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", ts, payload)
+	return fmt.Sprintf("t=%d,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoffWithJitterGrowsAndStaysBounded(t *testing.T) {
+	prevBase := time.Second
+	for attempt := 1; attempt < maxHookAttempts; attempt++ {
+		base := time.Second * time.Duration(1<<uint(attempt))
+		delay := backoffWithJitter(attempt)
+
+		if delay < base {
+			t.Fatalf("attempt %d: backoffWithJitter returned %s, want at least the base delay %s", attempt, delay, base)
+		}
+		if max := base + base/2; delay > max {
+			t.Fatalf("attempt %d: backoffWithJitter returned %s, want at most %s", attempt, delay, max)
+		}
+		if base <= prevBase && attempt > 1 {
+			t.Fatalf("attempt %d: base delay %s did not grow from previous attempt's %s", attempt, base, prevBase)
+		}
+		prevBase = base
+	}
+}
+
+func TestSignHookPayloadFormat(t *testing.T) {
+	secret := "whsec_test"
+	payload := []byte(`{"id":"ord_123"}`)
+	at := time.Unix(1700000000, 0)
+
+	sig := signHookPayload(secret, payload, at)
+
+	wantPrefix := "t=1700000000,v1="
+	if !strings.HasPrefix(sig, wantPrefix) {
+		t.Fatalf("signHookPayload() = %q, want prefix %q", sig, wantPrefix)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", at.Unix(), payload)
+	wantSig := fmt.Sprintf("t=%d,v1=%x", at.Unix(), mac.Sum(nil))
+	if sig != wantSig {
+		t.Fatalf("signHookPayload() = %q, want %q", sig, wantSig)
+	}
+}
+
+func TestSignHookPayloadDiffersByTimestamp(t *testing.T) {
+	secret := "whsec_test"
+	payload := []byte(`{"id":"ord_123"}`)
+
+	sigA := signHookPayload(secret, payload, time.Unix(1700000000, 0))
+	sigB := signHookPayload(secret, payload, time.Unix(1700000001, 0))
+
+	if sigA == sigB {
+		t.Fatal("signHookPayload() produced identical signatures for different timestamps")
+	}
+}
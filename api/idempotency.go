@@ -0,0 +1,339 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	gcontext "github.com/netlify/gocommerce/context"
+	"github.com/netlify/gocommerce/models"
+)
+
+// idempotencyKeyTTL is how long a stored response is replayed for before
+// the key can be reused with a new request.
+const idempotencyKeyTTL = 24 * time.Hour
+
+// idempotentResponse is what gets buffered and replayed for a repeated
+// request, and what gets persisted in the store.
+type idempotentResponse struct {
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	BodyHash string      `json:"body_hash"`
+}
+
+// idempotencyStore is the pluggable backend behind the idempotency
+// middleware, keyed by (instance, principal, method, path, Idempotency-Key).
+// Reserve and Get/Put together implement a two-phase claim: Reserve must
+// atomically create a placeholder for a key that isn't already in flight, so
+// two concurrent requests with the same key can't both win and both execute
+// the handler. Get reports a reserved-but-not-yet-completed key as found
+// with a nil response. Release discards a reservation that Put never
+// completed, so a key isn't wedged as permanently "in progress" for its
+// full TTL after a storage failure.
+type idempotencyStore interface {
+	Reserve(key string, ttl time.Duration) (bool, error)
+	Get(key string) (*idempotentResponse, bool, error)
+	Put(key string, resp *idempotentResponse, ttl time.Duration) error
+	Release(key string) error
+	DeleteExpired() error
+}
+
+// idempotency honors the Idempotency-Key request header on mutating
+// endpoints. The first request with a given key reserves it, is buffered,
+// and has its response persisted; later (or concurrent) requests with the
+// same key replay the stored response, or get a 409 if the original
+// request is still in flight, instead of re-executing the handler. A key
+// reused with a different request body is rejected with 409, since
+// replaying it would silently apply the wrong operation.
+func (a *API) idempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeIdempotencyError(w, http.StatusBadRequest, "invalid_body", "could not read request body")
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		bodyHash := hashBody(body)
+
+		storeKey := idempotencyStoreKey(r, key)
+
+		if stored, ok, err := a.idempotencyStore.Get(storeKey); err == nil && ok {
+			if stored == nil {
+				writeIdempotencyError(w, http.StatusConflict, "idempotency_key_in_progress", "a request with this Idempotency-Key is still being processed")
+				return
+			}
+			if stored.BodyHash != bodyHash {
+				writeIdempotencyError(w, http.StatusConflict, "idempotency_key_conflict", "Idempotency-Key was already used with a different request body")
+				return
+			}
+			replay(w, stored)
+			return
+		}
+
+		won, err := a.idempotencyStore.Reserve(storeKey, idempotencyKeyTTL)
+		if err != nil {
+			logrus.WithError(err).WithField("component", "idempotency").Error("failed to reserve idempotency key, executing handler unguarded")
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !won {
+			writeIdempotencyError(w, http.StatusConflict, "idempotency_key_in_progress", "a request with this Idempotency-Key is still being processed")
+			return
+		}
+
+		rec := &bufferingRecorder{ResponseWriter: w, status: http.StatusOK, header: http.Header{}}
+		next.ServeHTTP(rec, r)
+
+		stored := &idempotentResponse{
+			Status:   rec.status,
+			Header:   rec.header,
+			Body:     rec.body.Bytes(),
+			BodyHash: bodyHash,
+		}
+		if err := a.idempotencyStore.Put(storeKey, stored, idempotencyKeyTTL); err != nil {
+			log := logrus.WithError(err).WithField("component", "idempotency")
+			log.Error("failed to persist idempotent response; releasing the reservation so a retry can proceed")
+			if releaseErr := a.idempotencyStore.Release(storeKey); releaseErr != nil {
+				log.WithError(releaseErr).Error("failed to release idempotency key reservation after a failed Put; retries will be rejected until it expires")
+			}
+		}
+	})
+}
+
+func writeIdempotencyError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"code": code, "msg": message})
+}
+
+func idempotencyStoreKey(r *http.Request, key string) string {
+	instanceID := gcontext.GetInstanceID(r.Context())
+	principal := remoteIP(r)
+	if claims := gcontext.GetClaims(r.Context()); claims != nil {
+		principal = claims.Subject
+	}
+	return instanceID + ":" + principal + ":" + r.Method + ":" + r.URL.Path + ":" + key
+}
+
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func replay(w http.ResponseWriter, stored *idempotentResponse) {
+	for k, values := range stored.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(stored.Status)
+	w.Write(stored.Body)
+}
+
+// bufferingRecorder captures a handler's status, headers and body so they
+// can be persisted for replay once the handler has finished writing.
+type bufferingRecorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *bufferingRecorder) WriteHeader(status int) {
+	r.status = status
+	for k, values := range r.ResponseWriter.Header() {
+		r.header[k] = values
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *bufferingRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// inMemoryIdempotencyStore is the default backend, suitable for
+// single-instance deployments or tests.
+type inMemoryIdempotencyStore struct {
+	mutex   sync.Mutex
+	entries map[string]idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	response  *idempotentResponse
+	expiresAt time.Time
+}
+
+func newInMemoryIdempotencyStore() *inMemoryIdempotencyStore {
+	return &inMemoryIdempotencyStore{entries: map[string]idempotencyEntry{}}
+}
+
+func (s *inMemoryIdempotencyStore) Reserve(key string, ttl time.Duration) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if entry, ok := s.entries[key]; ok && time.Now().Before(entry.expiresAt) {
+		return false, nil
+	}
+	s.entries[key] = idempotencyEntry{expiresAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (s *inMemoryIdempotencyStore) Get(key string) (*idempotentResponse, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false, nil
+	}
+	return entry.response, true, nil
+}
+
+func (s *inMemoryIdempotencyStore) Put(key string, resp *idempotentResponse, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries[key] = idempotencyEntry{response: resp, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Release discards a reservation made by Reserve, so that a subsequent
+// request with the same key is treated as a fresh attempt rather than one
+// still in flight. Used when Put fails after the handler has already run.
+func (s *inMemoryIdempotencyStore) Release(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *inMemoryIdempotencyStore) DeleteExpired() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+// sqlIdempotencyStore persists buffered responses in the DB so the key
+// store survives restarts and is shared across every instance of a
+// multi-process deployment.
+type sqlIdempotencyStore struct {
+	db *gorm.DB
+}
+
+func newSQLIdempotencyStore(db *gorm.DB) *sqlIdempotencyStore {
+	return &sqlIdempotencyStore{db: db}
+}
+
+// Reserve atomically inserts a placeholder row for key, relying on the
+// unique index on IdempotencyKey.Key to reject a second insert while the
+// first request is still in flight: exactly one caller's Create succeeds.
+func (s *sqlIdempotencyStore) Reserve(key string, ttl time.Duration) (bool, error) {
+	// An expired row for key guards nothing any more; clear it so the key can
+	// be reserved again without waiting for DeleteExpired.
+	if err := s.db.Where("key = ? and expires_at <= ?", key, time.Now()).Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return false, err
+	}
+
+	record := &models.IdempotencyKey{
+		Key:       key,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	err := s.db.Create(record).Error
+	if err == nil {
+		return true, nil
+	}
+	if isDuplicateKeyError(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (s *sqlIdempotencyStore) Get(key string) (*idempotentResponse, bool, error) {
+	record := &models.IdempotencyKey{}
+	err := s.db.Where("key = ? and expires_at > ?", key, time.Now()).First(record).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(record.Response) == 0 {
+		return nil, true, nil
+	}
+
+	resp := &idempotentResponse{}
+	if err := json.Unmarshal(record.Response, resp); err != nil {
+		return nil, false, err
+	}
+	return resp, true, nil
+}
+
+// Put completes a reservation made by Reserve, filling in the response on
+// the placeholder row that Reserve already created.
+func (s *sqlIdempotencyStore) Put(key string, resp *idempotentResponse, ttl time.Duration) error {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return s.db.Model(&models.IdempotencyKey{}).Where("key = ?", key).Updates(map[string]interface{}{
+		"response":   body,
+		"expires_at": time.Now().Add(ttl),
+	}).Error
+}
+
+// Release deletes the placeholder row Reserve created, so a subsequent
+// request with the same key is treated as a fresh attempt rather than one
+// still in flight. Used when Put fails after the handler has already run.
+func (s *sqlIdempotencyStore) Release(key string) error {
+	return s.db.Where("key = ?", key).Delete(&models.IdempotencyKey{}).Error
+}
+
+// isDuplicateKeyError reports whether err looks like a unique constraint
+// violation, across the handful of SQL drivers gocommerce supports
+// (Postgres, MySQL, SQLite), without importing any of their driver
+// packages directly.
+func isDuplicateKeyError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "duplicate") || strings.Contains(msg, "unique constraint") || strings.Contains(msg, "unique_violation")
+}
+
+func (s *sqlIdempotencyStore) DeleteExpired() error {
+	return s.db.Where("expires_at <= ?", time.Now()).Delete(&models.IdempotencyKey{}).Error
+}
+
+// IdempotencyCleanup deletes expired idempotency keys from the store. It is
+// exposed for the admin cleanup job/cron rather than run inline on every
+// request.
+func (a *API) IdempotencyCleanup(w http.ResponseWriter, r *http.Request) error {
+	if err := a.idempotencyStore.DeleteExpired(); err != nil {
+		return internalServerError("Error cleaning up idempotency keys").WithInternalError(err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+
+	gcontext "github.com/netlify/gocommerce/context"
+	"github.com/netlify/gocommerce/models"
+)
+
+func newIdempotencyTestRequest(instanceID, remoteAddr string, claims *models.JWTClaims) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	r.RemoteAddr = remoteAddr
+
+	ctx := r.Context()
+	if instanceID != "" {
+		ctx = gcontext.WithInstanceID(ctx, instanceID)
+	}
+	if claims != nil {
+		ctx = gcontext.WithClaims(ctx, claims)
+	}
+	return r.WithContext(ctx)
+}
+
+func TestIdempotencyStoreKeyVariesByInstanceAndPrincipal(t *testing.T) {
+	base := newIdempotencyTestRequest("instance-a", "1.2.3.4:5678", nil)
+	differentInstance := newIdempotencyTestRequest("instance-b", "1.2.3.4:5678", nil)
+	differentIP := newIdempotencyTestRequest("instance-a", "9.9.9.9:1111", nil)
+	withClaims := newIdempotencyTestRequest("instance-a", "1.2.3.4:5678", &models.JWTClaims{StandardClaims: jwt.StandardClaims{Subject: "user-1"}})
+
+	keys := map[string]string{
+		"base":              idempotencyStoreKey(base, "key-1"),
+		"differentInstance": idempotencyStoreKey(differentInstance, "key-1"),
+		"differentIP":       idempotencyStoreKey(differentIP, "key-1"),
+		"withClaims":        idempotencyStoreKey(withClaims, "key-1"),
+	}
+
+	seen := map[string]string{}
+	for name, key := range keys {
+		if other, ok := seen[key]; ok {
+			t.Fatalf("idempotencyStoreKey(%s) collided with idempotencyStoreKey(%s): both produced %q", name, other, key)
+		}
+		seen[key] = name
+	}
+}
+
+func TestIdempotencyStoreKeyVariesByIdempotencyKey(t *testing.T) {
+	r := newIdempotencyTestRequest("instance-a", "1.2.3.4:5678", nil)
+
+	if idempotencyStoreKey(r, "key-1") == idempotencyStoreKey(r, "key-2") {
+		t.Fatal("idempotencyStoreKey produced the same key for two different Idempotency-Key values")
+	}
+}
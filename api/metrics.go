@@ -0,0 +1,209 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/jinzhu/gorm"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/netlify/gocommerce/conf"
+	gcontext "github.com/netlify/gocommerce/context"
+	"github.com/netlify/gocommerce/models"
+)
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gocommerce_http_requests_total",
+		Help: "Total number of HTTP requests handled, labeled by route, method, status and instance.",
+	}, []string{"route", "method", "status", "instance_id"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gocommerce_http_request_duration_seconds",
+		Help:    "HTTP request duration in seconds, labeled by route, method and instance.",
+		Buckets: defaultHistogramBuckets,
+	}, []string{"route", "method", "instance_id"})
+
+	httpRequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gocommerce_http_requests_in_flight",
+		Help: "Number of HTTP requests currently being served, labeled by route.",
+	}, []string{"route"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gocommerce_db_query_duration_seconds",
+		Help:    "Database query duration in seconds, labeled by table and operation.",
+		Buckets: defaultHistogramBuckets,
+	}, []string{"table", "operation"})
+
+	dbQueryErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gocommerce_db_query_errors_total",
+		Help: "Total number of database query errors, labeled by table and operation.",
+	}, []string{"table", "operation"})
+
+	ordersCreatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gocommerce_orders_created_total",
+		Help: "Total number of orders created.",
+	})
+
+	paymentsConfirmedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gocommerce_payments_confirmed_total",
+		Help: "Total number of payments confirmed, labeled by provider.",
+	}, []string{"provider"})
+
+	refundsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gocommerce_refunds_total",
+		Help: "Total number of payment refunds processed.",
+	})
+
+	couponRedemptionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gocommerce_coupon_redemptions_total",
+		Help: "Total number of coupons redeemed on an order.",
+	})
+)
+
+// defaultHistogramBuckets is used for both histograms unless
+// configureHistogramBuckets replaces them with the configured buckets from
+// conf.MetricsConfiguration, since the prometheus vectors above are
+// registered as package-level vars before any config is loaded.
+var defaultHistogramBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+func init() {
+	prometheus.MustRegister(
+		httpRequestsTotal,
+		httpRequestDuration,
+		httpRequestsInFlight,
+		dbQueryDuration,
+		dbQueryErrorsTotal,
+		ordersCreatedTotal,
+		paymentsConfirmedTotal,
+		refundsTotal,
+		couponRedemptionsTotal,
+	)
+}
+
+// configureHistogramBuckets re-registers the duration histograms with the
+// buckets from config.Metrics.Buckets, if any were configured. It must run
+// once, before the server starts taking traffic, since prometheus histogram
+// bucket boundaries can't be changed after the first observation.
+func configureHistogramBuckets(config *conf.GlobalConfiguration) {
+	buckets := config.Metrics.Buckets
+	if len(buckets) == 0 {
+		return
+	}
+
+	prometheus.Unregister(httpRequestDuration)
+	prometheus.Unregister(dbQueryDuration)
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gocommerce_http_request_duration_seconds",
+		Help:    "HTTP request duration in seconds, labeled by route, method and instance.",
+		Buckets: buckets,
+	}, []string{"route", "method", "instance_id"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gocommerce_db_query_duration_seconds",
+		Help:    "Database query duration in seconds, labeled by table and operation.",
+		Buckets: buckets,
+	}, []string{"table", "operation"})
+
+	prometheus.MustRegister(httpRequestDuration, dbQueryDuration)
+}
+
+// metricsHandler serves the /metrics route. It bypasses auth and CORS, like
+// /health, since scrapers are typically cluster-internal and unauthenticated.
+func metricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// instrumentRequests is the RED (rate/errors/duration) middleware installed
+// in NewAPIWithVersion. It records request count and duration labeled by
+// chi's matched route pattern rather than the raw path, so cardinality
+// stays bounded. The in-flight gauge is labeled by the raw request path,
+// since the route has not been matched yet when a request starts. The
+// instance_id label is read from gcontext on the request as it reaches this
+// middleware, same as tracingHandler; loadInstanceConfig sets the instance
+// ID further down the chain, so the label is empty.
+func instrumentRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpRequestsInFlight.WithLabelValues(r.URL.Path).Inc()
+		defer httpRequestsInFlight.WithLabelValues(r.URL.Path).Dec()
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		route := r.URL.Path
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		instanceID := gcontext.GetInstanceID(r.Context())
+		httpRequestDuration.WithLabelValues(route, r.Method, instanceID).Observe(time.Since(start).Seconds())
+		httpRequestsTotal.WithLabelValues(route, r.Method, statusLabel(rec.status), instanceID).Inc()
+	})
+}
+
+// recordBusinessEvent updates the order/payment/coupon counters from the
+// same lifecycle events that drive the webhook dispatcher, so the two stay
+// in lockstep without duplicating call sites in every handler.
+func recordBusinessEvent(eventType string, payload interface{}) {
+	switch eventType {
+	case "order.created":
+		ordersCreatedTotal.Inc()
+	case "payment.confirmed":
+		provider := "unknown"
+		if tx, ok := payload.(*models.Transaction); ok && tx.Processor != "" {
+			provider = tx.Processor
+		}
+		paymentsConfirmedTotal.WithLabelValues(provider).Inc()
+	case "payment.refunded":
+		refundsTotal.Inc()
+	case "coupon.redeemed":
+		couponRedemptionsTotal.Inc()
+	}
+}
+
+func statusLabel(status int) string {
+	switch {
+	case status >= 500:
+		return "5xx"
+	case status >= 400:
+		return "4xx"
+	case status >= 300:
+		return "3xx"
+	default:
+		return "2xx"
+	}
+}
+
+// gormMetricsPlugin registers gorm callbacks that record query duration and
+// error rate for every DB call the API makes.
+func gormMetricsPlugin(db *gorm.DB) {
+	before := func(scope *gorm.Scope) {
+		scope.Set("metrics:start_time", time.Now())
+	}
+	after := func(operation string) func(scope *gorm.Scope) {
+		return func(scope *gorm.Scope) {
+			startTime, ok := scope.Get("metrics:start_time")
+			if !ok {
+				return
+			}
+			duration := time.Since(startTime.(time.Time)).Seconds()
+			dbQueryDuration.WithLabelValues(scope.TableName(), operation).Observe(duration)
+			if scope.HasError() {
+				dbQueryErrorsTotal.WithLabelValues(scope.TableName(), operation).Inc()
+			}
+		}
+	}
+
+	db.Callback().Create().Before("gorm:create").Register("metrics:before_create", before)
+	db.Callback().Create().After("gorm:create").Register("metrics:after_create", after("create"))
+	db.Callback().Query().Before("gorm:query").Register("metrics:before_query", before)
+	db.Callback().Query().After("gorm:query").Register("metrics:after_query", after("query"))
+	db.Callback().Update().Before("gorm:update").Register("metrics:before_update", before)
+	db.Callback().Update().After("gorm:update").Register("metrics:after_update", after("update"))
+	db.Callback().Delete().Before("gorm:delete").Register("metrics:before_delete", before)
+	db.Callback().Delete().After("gorm:delete").Register("metrics:after_delete", after("delete"))
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	gcontext "github.com/netlify/gocommerce/context"
+	"github.com/netlify/gocommerce/models"
+)
+
+// OrderCreate creates a new order for the authenticated user, or for a
+// guest checkout if no user is present on the request.
+func (a *API) OrderCreate(w http.ResponseWriter, r *http.Request) error {
+	params := struct {
+		Email    string `json:"email"`
+		Currency string `json:"currency"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return badRequestError("Could not read order params: %v", err)
+	}
+	if params.Email == "" {
+		return badRequestError("An order requires an email address")
+	}
+
+	order := &models.Order{
+		InstanceID:   gcontext.GetInstanceID(r.Context()),
+		Email:        params.Email,
+		Currency:     params.Currency,
+		PaymentState: models.PendingState,
+	}
+	if claims := gcontext.GetClaims(r.Context()); claims != nil {
+		order.UserID = claims.Subject
+	}
+
+	if err := a.db.Create(order).Error; err != nil {
+		return internalServerError("Error creating order").WithInternalError(err)
+	}
+
+	a.enqueueHookEvent(order.InstanceID, "order.created", order)
+	return sendJSON(w, http.StatusCreated, order)
+}
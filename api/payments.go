@@ -0,0 +1,169 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jinzhu/gorm"
+
+	gcontext "github.com/netlify/gocommerce/context"
+	"github.com/netlify/gocommerce/models"
+)
+
+// paymentProvider is the subset of the Stripe/PayPal client surface the
+// payment handlers need. stripeProvider and paypalProvider in providers.go
+// implement it against the real APIs.
+type paymentProvider interface {
+	Confirm(processorID string) error
+	Refund(processorID string, amount int64) error
+}
+
+// providerFor resolves the paymentProvider for a transaction's Processor
+// field ("stripe" or "paypal").
+func (a *API) providerFor(processor string) (paymentProvider, error) {
+	switch processor {
+	case "stripe":
+		return newStripeProvider(a.httpClient, a.config), nil
+	case "paypal":
+		return newPaypalProvider(a.httpClient, a.config), nil
+	default:
+		return nil, fmt.Errorf("unknown payment processor %q", processor)
+	}
+}
+
+// PaymentConfirm confirms a pending payment with the provider it was
+// created against, and only marks it paid once the provider confirms the
+// charge actually succeeded. The REST and gRPC transports both call
+// ConfirmPayment so they can never diverge on what "confirmed" means.
+func (a *API) PaymentConfirm(w http.ResponseWriter, r *http.Request) error {
+	tx, err := a.ConfirmPayment(gcontext.GetInstanceID(r.Context()), routeParam(r, "payment_id"))
+	if err != nil {
+		return apiErrorFor(err)
+	}
+	return sendJSON(w, http.StatusOK, tx)
+}
+
+// PaymentRefund refunds a payment through the provider it was charged with,
+// and only marks it refunded once the provider confirms the funds were
+// actually returned. The REST and gRPC transports both call RefundPayment
+// so they can never diverge on what "refunded" means.
+func (a *API) PaymentRefund(w http.ResponseWriter, r *http.Request) error {
+	params := struct {
+		Amount int64 `json:"amount"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return badRequestError("Could not read refund params: %v", err)
+	}
+
+	tx, err := a.RefundPayment(gcontext.GetInstanceID(r.Context()), routeParam(r, "payment_id"), params.Amount)
+	if err != nil {
+		return apiErrorFor(err)
+	}
+	return sendJSON(w, http.StatusOK, tx)
+}
+
+// ConfirmPayment is the shared business logic behind the REST PaymentConfirm
+// handler and the gRPC PaymentService.ConfirmPayment RPC: look the
+// transaction up, ask its provider to confirm the charge, and only then
+// flip it to paid. It is exported, like the other peer-transport hooks in
+// grpc_support.go, so the gRPC transport never has to reimplement payment
+// state transitions against the DB directly. Rejects an already-paid
+// transaction outright, since the Idempotency-Key header is optional and a
+// retry without one must not re-confirm (and double-capture) a charge.
+func (a *API) ConfirmPayment(instanceID, paymentID string) (*models.Transaction, error) {
+	tx := &models.Transaction{}
+	if err := a.db.Where("id = ?", paymentID).First(tx).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, NotFoundError{"Payment not found"}
+		}
+		return nil, err
+	}
+
+	if tx.Status == models.PaidState {
+		return nil, BadRequestError{"Payment has already been confirmed"}
+	}
+
+	provider, err := a.providerFor(tx.Processor)
+	if err != nil {
+		return nil, BadRequestError{err.Error()}
+	}
+	if err := provider.Confirm(tx.ProcessorID); err != nil {
+		return nil, fmt.Errorf("confirming payment with provider: %w", err)
+	}
+
+	if err := a.db.Model(tx).Update("status", models.PaidState).Error; err != nil {
+		return nil, err
+	}
+
+	a.enqueueHookEvent(instanceID, "payment.confirmed", tx)
+	return tx, nil
+}
+
+// RefundPayment is the shared business logic behind the REST PaymentRefund
+// handler and the gRPC PaymentService.RefundPayment RPC: look the
+// transaction up, ask its provider to return the funds, and only then flip
+// it to refunded. A zero amount refunds the transaction's full amount.
+// Requires the transaction to be currently paid, for the same
+// double-submission reasons as ConfirmPayment.
+func (a *API) RefundPayment(instanceID, paymentID string, amount int64) (*models.Transaction, error) {
+	tx := &models.Transaction{}
+	if err := a.db.Where("id = ?", paymentID).First(tx).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, NotFoundError{"Payment not found"}
+		}
+		return nil, err
+	}
+
+	if tx.Status == models.RefundedState {
+		return nil, BadRequestError{"Payment has already been refunded"}
+	}
+	if tx.Status != models.PaidState {
+		return nil, BadRequestError{"Only a paid payment can be refunded"}
+	}
+
+	if amount == 0 {
+		amount = tx.Amount
+	}
+
+	provider, err := a.providerFor(tx.Processor)
+	if err != nil {
+		return nil, BadRequestError{err.Error()}
+	}
+	if err := provider.Refund(tx.ProcessorID, amount); err != nil {
+		return nil, fmt.Errorf("refunding payment with provider: %w", err)
+	}
+
+	if err := a.db.Model(tx).Update("status", models.RefundedState).Error; err != nil {
+		return nil, err
+	}
+
+	a.enqueueHookEvent(instanceID, "payment.refunded", tx)
+	return tx, nil
+}
+
+// NotFoundError and BadRequestError let ConfirmPayment/RefundPayment signal
+// their error kind to both callers: apiErrorFor maps them to the
+// REST-specific HTTPError codes, and the grpc package (which cannot see
+// HTTPError's REST-flavored status codes) maps them to grpc/codes instead
+// via errors.As.
+type NotFoundError struct{ Message string }
+
+func (e NotFoundError) Error() string { return e.Message }
+
+type BadRequestError struct{ Message string }
+
+func (e BadRequestError) Error() string { return e.Message }
+
+// apiErrorFor maps the sentinel errors above to the HTTPError REST handlers
+// return; any other error is treated as internal.
+func apiErrorFor(err error) error {
+	switch e := err.(type) {
+	case NotFoundError:
+		return notFoundError(e.Message)
+	case BadRequestError:
+		return badRequestError(e.Message)
+	default:
+		return internalServerError("Error processing payment").WithInternalError(err)
+	}
+}
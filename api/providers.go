@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/netlify/gocommerce/conf"
+)
+
+// stripeProvider confirms and refunds charges through the Stripe API.
+type stripeProvider struct {
+	httpClient *http.Client
+	secretKey  string
+}
+
+func newStripeProvider(httpClient *http.Client, config *conf.GlobalConfiguration) *stripeProvider {
+	return &stripeProvider{httpClient: httpClient, secretKey: config.Payment.StripeSecretKey}
+}
+
+func (p *stripeProvider) Confirm(processorID string) error {
+	return p.post("https://api.stripe.com/v1/payment_intents/"+processorID+"/confirm", nil)
+}
+
+func (p *stripeProvider) Refund(processorID string, amount int64) error {
+	form := url.Values{"payment_intent": {processorID}, "amount": {strconv.FormatInt(amount, 10)}}
+	return p.post("https://api.stripe.com/v1/refunds", form)
+}
+
+func (p *stripeProvider) post(endpoint string, form url.Values) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if form != nil {
+		req.URL.RawQuery = form.Encode()
+	}
+	req.SetBasicAuth(p.secretKey, "")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("stripe request to %s failed with status %d", endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+// paypalProvider confirms and refunds payments through the PayPal API.
+type paypalProvider struct {
+	httpClient *http.Client
+	clientID   string
+	secret     string
+}
+
+func newPaypalProvider(httpClient *http.Client, config *conf.GlobalConfiguration) *paypalProvider {
+	return &paypalProvider{
+		httpClient: httpClient,
+		clientID:   config.Payment.PaypalClientID,
+		secret:     config.Payment.PaypalSecret,
+	}
+}
+
+func (p *paypalProvider) Confirm(processorID string) error {
+	return p.post("https://api.paypal.com/v2/checkout/orders/" + processorID + "/capture")
+}
+
+func (p *paypalProvider) Refund(processorID string, amount int64) error {
+	return p.post("https://api.paypal.com/v2/payments/captures/" + processorID + "/refund")
+}
+
+func (p *paypalProvider) post(endpoint string) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.clientID, p.secret)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("paypal request to %s failed with status %d", endpoint, resp.StatusCode)
+	}
+	return nil
+}
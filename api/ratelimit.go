@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	redis "github.com/go-redis/redis"
+
+	gcontext "github.com/netlify/gocommerce/context"
+)
+
+// rateLimitStore is the pluggable backend behind rateLimiter. Take returns
+// the number of remaining tokens after consuming one, the time at which the
+// bucket next refills, and whether the request should be allowed.
+type rateLimitStore interface {
+	Take(key string, limit int, window time.Duration) (remaining int, resetAt time.Time, allowed bool)
+}
+
+// rateLimitBucket describes the limit applied to a given scope (IP, user or
+// admin).
+type rateLimitBucket struct {
+	Limit  int
+	Window time.Duration
+}
+
+// rateLimiter enforces the per-IP and per-user limits configured under
+// config.RateLimit. Admin-authenticated requests use the elevated AdminLimit
+// instead. In multi-instance mode each bucket key is prefixed with the
+// instance ID, so instances do not share buckets; there is no limit on an
+// instance's total traffic.
+func (a *API) rateLimiter(w http.ResponseWriter, r *http.Request) (context.Context, error) {
+	rlConfig := a.config.RateLimit
+	if !rlConfig.Enabled {
+		return r.Context(), nil
+	}
+
+	window := time.Duration(rlConfig.WindowSeconds) * time.Second
+	bucket := rateLimitBucket{Limit: rlConfig.PerIPLimit, Window: window}
+	key := "ip:" + remoteIP(r)
+
+	if claims := gcontext.GetClaims(r.Context()); claims != nil {
+		if claims.HasRole(rlConfig.AdminGroupName) {
+			bucket = rateLimitBucket{Limit: rlConfig.AdminLimit, Window: window}
+			key = "admin:" + claims.Subject
+		} else {
+			bucket = rateLimitBucket{Limit: rlConfig.PerUserLimit, Window: window}
+			key = "user:" + claims.Subject
+		}
+	}
+
+	if instanceID := gcontext.GetInstanceID(r.Context()); instanceID != "" {
+		key = instanceID + ":" + key
+	}
+
+	remaining, resetAt, allowed := a.rateLimitStore.Take(key, bucket.Limit, bucket.Window)
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(bucket.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+	if !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(resetAt).Seconds())))
+		return nil, httpError(http.StatusTooManyRequests, "rate_limit_exceeded", "rate limit of %d requests per %s exceeded", bucket.Limit, bucket.Window)
+	}
+
+	return r.Context(), nil
+}
+
+// inMemoryRateLimitStore is a token-bucket implementation suitable for
+// single-instance deployments or tests. Each key gets its own bucket that
+// refills fully at the start of every window.
+type inMemoryRateLimitStore struct {
+	mutex   sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+type memoryBucket struct {
+	remaining int
+	resetAt   time.Time
+}
+
+func newInMemoryRateLimitStore() *inMemoryRateLimitStore {
+	return &inMemoryRateLimitStore{buckets: map[string]*memoryBucket{}}
+}
+
+func (s *inMemoryRateLimitStore) Take(key string, limit int, window time.Duration) (int, time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	b, ok := s.buckets[key]
+	if !ok || now.After(b.resetAt) {
+		b = &memoryBucket{remaining: limit, resetAt: now.Add(window)}
+		s.buckets[key] = b
+	}
+
+	if b.remaining <= 0 {
+		return 0, b.resetAt, false
+	}
+	b.remaining--
+	return b.remaining, b.resetAt, true
+}
+
+// redisRateLimitStore backs the same interface with Redis INCR/EXPIRE so
+// limits are shared across every instance of a multi-process deployment.
+type redisRateLimitStore struct {
+	client redisClient
+}
+
+// redisClient is the subset of go-redis used by redisRateLimitStore, kept
+// narrow so tests can fake it without pulling in a real client.
+type redisClient interface {
+	Incr(key string) (int64, error)
+	Expire(key string, ttl time.Duration) error
+	TTL(key string) (time.Duration, error)
+}
+
+func newRedisRateLimitStore(client redisClient) *redisRateLimitStore {
+	return &redisRateLimitStore{client: client}
+}
+
+// goRedisClient adapts github.com/go-redis/redis to the narrow redisClient
+// interface redisRateLimitStore depends on.
+type goRedisClient struct {
+	client *redis.Client
+}
+
+func newRedisClient(url string) *goRedisClient {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		opts = &redis.Options{Addr: url}
+	}
+	return &goRedisClient{client: redis.NewClient(opts)}
+}
+
+func (c *goRedisClient) Incr(key string) (int64, error) {
+	return c.client.Incr(key).Result()
+}
+
+func (c *goRedisClient) Expire(key string, ttl time.Duration) error {
+	return c.client.Expire(key, ttl).Err()
+}
+
+func (c *goRedisClient) TTL(key string) (time.Duration, error) {
+	return c.client.TTL(key).Result()
+}
+
+func (s *redisRateLimitStore) Take(key string, limit int, window time.Duration) (int, time.Time, bool) {
+	count, err := s.client.Incr(key)
+	if err != nil {
+		// Fail open: a transient store error shouldn't take the API down.
+		return limit, time.Now().Add(window), true
+	}
+	if count == 1 {
+		s.client.Expire(key, window)
+	}
+
+	ttl, err := s.client.TTL(key)
+	if err != nil || ttl <= 0 {
+		ttl = window
+	}
+	resetAt := time.Now().Add(ttl)
+
+	remaining := int(int64(limit) - count)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, resetAt, count <= int64(limit)
+}
+
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
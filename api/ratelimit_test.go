@@ -0,0 +1,39 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInMemoryRateLimitStoreTake(t *testing.T) {
+	store := newInMemoryRateLimitStore()
+
+	remaining, _, allowed := store.Take("key", 2, time.Minute)
+	if !allowed || remaining != 1 {
+		t.Fatalf("expected first request to be allowed with 1 remaining, got allowed=%v remaining=%d", allowed, remaining)
+	}
+
+	remaining, _, allowed = store.Take("key", 2, time.Minute)
+	if !allowed || remaining != 0 {
+		t.Fatalf("expected second request to be allowed with 0 remaining, got allowed=%v remaining=%d", allowed, remaining)
+	}
+
+	_, resetAt, allowed := store.Take("key", 2, time.Minute)
+	if allowed {
+		t.Fatal("expected third request within the window to be rejected")
+	}
+	if !resetAt.After(time.Now()) {
+		t.Fatal("expected resetAt to be in the future")
+	}
+}
+
+func TestInMemoryRateLimitStoreTakeSeparateKeys(t *testing.T) {
+	store := newInMemoryRateLimitStore()
+
+	if _, _, allowed := store.Take("a", 1, time.Minute); !allowed {
+		t.Fatal("expected key a to be allowed")
+	}
+	if _, _, allowed := store.Take("b", 1, time.Minute); !allowed {
+		t.Fatal("expected key b to be allowed independently of key a")
+	}
+}
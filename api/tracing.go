@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	b3 "go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/netlify/gocommerce/conf"
+	gcontext "github.com/netlify/gocommerce/context"
+)
+
+const tracerName = "github.com/netlify/gocommerce/api"
+
+// initTracer builds the OTLP exporter and span processor described by
+// config.Tracing and installs it as the global TracerProvider. The returned
+// func must be called once the server has stopped accepting new requests so
+// buffered spans are flushed before the process exits.
+func initTracer(config *conf.GlobalConfiguration) (func(ctx context.Context) error, error) {
+	tracingConfig := config.Tracing
+	if !tracingConfig.Enabled {
+		return func(ctx context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(context.Background(), otlptracegrpc.WithEndpoint(tracingConfig.OTLPEndpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(semconv.ServiceNameKey.String(tracingConfig.ServiceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(tracingConfig.SamplerRatio))),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+		b3.New(),
+	))
+
+	return provider.Shutdown, nil
+}
+
+// tracingHandler opens a server span for every request, extracting any W3C
+// traceparent or B3 headers already present so the span joins an upstream
+// trace. The request ID set by withRequestID, which runs before this
+// handler, is attached as a span attribute, and so is an instance ID already
+// on the incoming request's context. loadInstanceConfig sets the instance ID
+// further down the chain, after these attributes are set, so in
+// multi-instance mode the span carries no instance_id.
+func tracingHandler(next http.Handler) http.Handler {
+	tracer := otel.Tracer(tracerName)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracer.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		span.SetAttributes(
+			semconv.HTTPMethodKey.String(r.Method),
+			semconv.HTTPTargetKey.String(r.URL.Path),
+		)
+		if id := gcontext.GetRequestID(r.Context()); id != "" {
+			span.SetAttributes(attribute.String("request_id", id))
+		}
+		if instanceID := gcontext.GetInstanceID(r.Context()); instanceID != "" {
+			span.SetAttributes(attribute.String("instance_id", instanceID))
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetAttributes(attribute.String("http.route", rctx.RoutePattern()))
+		}
+
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
+
+// statusRecorder captures the status code written by downstream handlers so
+// it can be attached to the request span once the handler chain returns.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// tracedHTTPClient wraps an *http.Client so outbound Stripe, PayPal and VAT
+// lookup calls become child spans of whatever request triggered them.
+func tracedHTTPClient(client *http.Client) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	wrapped := *client
+	wrapped.Transport = otelTransport{base: base}
+	return &wrapped
+}
+
+type otelTransport struct {
+	base http.RoundTripper
+}
+
+func (t otelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), req.URL.Host, trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	return resp, nil
+}
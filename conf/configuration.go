@@ -0,0 +1,67 @@
+// Package conf loads gocommerce's runtime configuration from environment
+// variables (and, in multi-instance mode, from each instance's stored
+// configuration) into a single GlobalConfiguration struct.
+package conf
+
+// JWTConfiguration describes how incoming bearer tokens are verified.
+type JWTConfiguration struct {
+	Secret         string `envconfig:"JWT_SECRET" json:"secret"`
+	AdminGroupName string `envconfig:"JWT_ADMIN_GROUP_NAME" json:"admin_group_name" default:"admin"`
+}
+
+// TracingConfiguration controls the OpenTelemetry tracer installed by
+// api.initTracer.
+type TracingConfiguration struct {
+	Enabled      bool    `envconfig:"TRACING_ENABLED" json:"enabled"`
+	OTLPEndpoint string  `envconfig:"TRACING_OTLP_ENDPOINT" json:"otlp_endpoint"`
+	SamplerRatio float64 `envconfig:"TRACING_SAMPLER_RATIO" json:"sampler_ratio" default:"1.0"`
+	ServiceName  string  `envconfig:"TRACING_SERVICE_NAME" json:"service_name" default:"gocommerce"`
+}
+
+// RateLimitConfiguration controls api.rateLimiter.
+type RateLimitConfiguration struct {
+	Enabled        bool   `envconfig:"RATE_LIMIT_ENABLED" json:"enabled"`
+	PerIPLimit     int    `envconfig:"RATE_LIMIT_PER_IP" json:"per_ip_limit" default:"60"`
+	PerUserLimit   int    `envconfig:"RATE_LIMIT_PER_USER" json:"per_user_limit" default:"120"`
+	AdminLimit     int    `envconfig:"RATE_LIMIT_ADMIN" json:"admin_limit" default:"1000"`
+	WindowSeconds  int    `envconfig:"RATE_LIMIT_WINDOW_SECONDS" json:"window_seconds" default:"60"`
+	RedisURL       string `envconfig:"RATE_LIMIT_REDIS_URL" json:"redis_url"`
+	AdminGroupName string `envconfig:"RATE_LIMIT_ADMIN_GROUP_NAME" json:"admin_group_name" default:"admin"`
+}
+
+// MetricsConfiguration controls the Prometheus instrumentation installed by
+// api.gormMetricsPlugin/api.instrumentRequests, and the standalone admin
+// server metricsHandler is mounted on.
+type MetricsConfiguration struct {
+	AdminPort string    `envconfig:"METRICS_ADMIN_PORT" json:"admin_port"`
+	Buckets   []float64 `envconfig:"METRICS_BUCKETS" json:"buckets"`
+}
+
+// PaymentConfiguration holds the credentials used to talk to the payment
+// providers backing a transaction's Processor field.
+type PaymentConfiguration struct {
+	StripeSecretKey string `envconfig:"STRIPE_SECRET_KEY" json:"stripe_secret_key"`
+	PaypalClientID  string `envconfig:"PAYPAL_CLIENT_ID" json:"paypal_client_id"`
+	PaypalSecret    string `envconfig:"PAYPAL_SECRET" json:"paypal_secret"`
+}
+
+// DownloadsConfiguration controls api.freshDownloadURL's signing of
+// time-limited download links.
+type DownloadsConfiguration struct {
+	SigningSecret string `envconfig:"DOWNLOADS_SIGNING_SECRET" json:"signing_secret"`
+	URLTTLSeconds int    `envconfig:"DOWNLOADS_URL_TTL_SECONDS" json:"url_ttl_seconds" default:"86400"`
+}
+
+// GlobalConfiguration is the root configuration object, loaded once at
+// startup and threaded through api.NewAPIWithVersion.
+type GlobalConfiguration struct {
+	MultiInstanceMode bool   `envconfig:"MULTI_INSTANCE_MODE" json:"multi_instance_mode"`
+	InstanceID        string `envconfig:"INSTANCE_ID" json:"instance_id"`
+
+	JWT       JWTConfiguration       `json:"jwt"`
+	Tracing   TracingConfiguration   `json:"tracing"`
+	RateLimit RateLimitConfiguration `json:"rate_limit"`
+	Metrics   MetricsConfiguration   `json:"metrics"`
+	Payment   PaymentConfiguration   `json:"payment"`
+	Downloads DownloadsConfiguration `json:"downloads"`
+}